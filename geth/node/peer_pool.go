@@ -0,0 +1,331 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/ethereum/go-ethereum/p2p/discv5"
+)
+
+// Default bounds used for any topic that does not set its own limits.
+const (
+	DefaultMaxPeersPerTopic = 5
+	DefaultMinPeersPerTopic = 2
+
+	slowSearchPeriod = 20 * time.Second
+	fastSearchPeriod = 3 * time.Second
+)
+
+// PeerPoolEventType identifies the kind of PeerPoolEvent emitted on Events().
+type PeerPoolEventType string
+
+const (
+	// PeerFound is emitted when discv5 search returns a candidate node for a topic.
+	PeerFound PeerPoolEventType = "peer.found"
+	// PeerAdded is emitted once a found peer has been handed to the p2p server.
+	PeerAdded PeerPoolEventType = "peer.added"
+	// PeerDropped is emitted when the p2p server reports that a pooled peer disconnected.
+	PeerDropped PeerPoolEventType = "peer.dropped"
+)
+
+// PeerPoolEvent describes a single state change for a peer within a topic.
+type PeerPoolEvent struct {
+	Type  PeerPoolEventType
+	Topic discv5.Topic
+	Peer  discv5.NodeID
+}
+
+// TopicLimits bounds how many peers PeerPool will keep connected for a topic.
+// Search is kept hot (fast period) while the connected count is below Min and
+// backs off to a slow period once Max is reached.
+type TopicLimits struct {
+	Min int
+	Max int
+}
+
+// topicState tracks the peers currently known for a single topic, along with
+// the handles needed to control its search goroutine.
+type topicState struct {
+	limits TopicLimits
+	peers  map[discv5.NodeID]*discv5.Node
+	period chan time.Duration
+	quit   chan struct{}
+}
+
+// PeerPool discovers peers advertising a set of discv5 topics and feeds them
+// into a running p2p.Server, complementing Register which only advertises.
+type PeerPool struct {
+	cache TopicCache
+
+	mu     sync.Mutex
+	topics map[discv5.Topic]*topicState
+
+	events chan PeerPoolEvent
+
+	server *p2p.Server
+	quit   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewPeerPool creates a PeerPool that will search for peers on topics, using
+// limits to bound the peer count per topic (DefaultMinPeersPerTopic and
+// DefaultMaxPeersPerTopic are used for any topic missing from limits).
+// cache may be nil, in which case discovered peers are not persisted and
+// Start has nothing to warm-start a topic's search with.
+func NewPeerPool(topics []discv5.Topic, limits map[discv5.Topic]TopicLimits, cache TopicCache) *PeerPool {
+	states := make(map[discv5.Topic]*topicState, len(topics))
+	for _, topic := range topics {
+		l, ok := limits[topic]
+		if !ok {
+			l = TopicLimits{Min: DefaultMinPeersPerTopic, Max: DefaultMaxPeersPerTopic}
+		}
+		states[topic] = &topicState{
+			limits: l,
+			peers:  make(map[discv5.NodeID]*discv5.Node),
+		}
+	}
+	return &PeerPool{
+		cache:  cache,
+		topics: states,
+		events: make(chan PeerPoolEvent, 256),
+	}
+}
+
+// Events returns a channel emitting PeerFound/PeerAdded/PeerDropped events.
+// The channel drops the oldest pending event on overflow rather than
+// blocking callers that found/added/dropped a peer, mirroring Register's
+// Events().
+func (p *PeerPool) Events() <-chan PeerPoolEvent {
+	return p.events
+}
+
+// emit delivers event to Events(), dropping the oldest pending event if the
+// buffer is full rather than blocking the caller.
+func (p *PeerPool) emit(event PeerPoolEvent) {
+	select {
+	case p.events <- event:
+	default:
+		select {
+		case <-p.events:
+		default:
+		}
+		select {
+		case p.events <- event:
+		default:
+		}
+	}
+}
+
+// Start spawns a per-topic search goroutine, as well as a goroutine watching
+// the server for dropped peers so that search can be re-triggered once a
+// topic falls below its minimum.
+func (p *PeerPool) Start(server *p2p.Server) error {
+	if server.DiscV5 == nil {
+		return ErrDiscv5NotRunning
+	}
+	p.server = server
+	p.quit = make(chan struct{})
+
+	p.mu.Lock()
+	for _, state := range p.topics {
+		state.peers = make(map[discv5.NodeID]*discv5.Node)
+		state.quit = make(chan struct{})
+		state.period = make(chan time.Duration, 1)
+	}
+	p.mu.Unlock()
+
+	// seedFromCache goes through addPeer, which takes p.mu itself.
+	for topic, state := range p.topics {
+		p.seedFromCache(topic, state)
+		p.wg.Add(1)
+		go p.searchTopic(topic, state)
+	}
+
+	p.wg.Add(1)
+	go p.watchDroppedPeers()
+
+	return nil
+}
+
+// Stop terminates every topic search and waits for all goroutines to exit.
+func (p *PeerPool) Stop() {
+	if p.quit == nil {
+		return
+	}
+	select {
+	case <-p.quit:
+		return
+	default:
+	}
+	close(p.quit)
+
+	p.mu.Lock()
+	for _, state := range p.topics {
+		close(state.quit)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// seedFromCache feeds any previously cached nodes for topic into the server
+// immediately, before the first SearchTopic round has a chance to return
+// results, so that a cold-started client does not wait on discovery alone.
+func (p *PeerPool) seedFromCache(topic discv5.Topic, state *topicState) {
+	if p.cache == nil {
+		return
+	}
+	for _, node := range p.cache.Get(topic) {
+		p.addPeer(topic, state, node)
+	}
+}
+
+// searchTopic drives discv5.SearchTopic for a single topic, funnelling found
+// nodes into the server and adjusting the search period as the peer count
+// crosses the topic's Min/Max bounds. Closing state.period, as with
+// RegisterTopic's quit channel, is what tells the underlying discv5 search
+// goroutine to return, so it is closed here (under p.mu, see speedUpSearch)
+// once this goroutine stops driving the topic.
+func (p *PeerPool) searchTopic(topic discv5.Topic, state *topicState) {
+	defer p.wg.Done()
+
+	found := make(chan *discv5.Node, 10)
+	lookup := make(chan []*discv5.Node, 10)
+
+	state.period <- fastSearchPeriod
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.server.DiscV5.SearchTopic(topic, state.period, found, lookup)
+	}()
+	defer func() {
+		p.mu.Lock()
+		close(state.period)
+		p.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-state.quit:
+			return
+		case <-p.quit:
+			return
+		case node := <-found:
+			p.emit(PeerPoolEvent{Type: PeerFound, Topic: topic, Peer: node.ID})
+			p.addPeer(topic, state, node)
+			state.period <- p.nextPeriod(state)
+		case <-lookup:
+			// lookup results are only used to keep the discv5 search alive;
+			// peer accounting happens off the found channel.
+		}
+	}
+}
+
+func (p *PeerPool) nextPeriod(state *topicState) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(state.peers) >= state.limits.Max {
+		return slowSearchPeriod
+	}
+	return fastSearchPeriod
+}
+
+func (p *PeerPool) addPeer(topic discv5.Topic, state *topicState, node *discv5.Node) {
+	p.mu.Lock()
+	if _, exists := state.peers[node.ID]; exists {
+		p.mu.Unlock()
+		return
+	}
+	if len(state.peers) >= state.limits.Max {
+		p.mu.Unlock()
+		return
+	}
+	state.peers[node.ID] = node
+	p.mu.Unlock()
+
+	if p.cache != nil {
+		p.cache.Put(topic, node)
+	}
+
+	p.server.AddPeer(discover.NewNode(discover.NodeID(node.ID), node.IP, node.UDP, node.TCP))
+	p.emit(PeerPoolEvent{Type: PeerAdded, Topic: topic, Peer: node.ID})
+}
+
+// watchDroppedPeers subscribes to p2p.Server peer events and drops a peer
+// from every topic it was tracked under, re-triggering search for any topic
+// that falls below its Min threshold.
+func (p *PeerPool) watchDroppedPeers() {
+	defer p.wg.Done()
+
+	events := make(chan *p2p.PeerEvent, 10)
+	sub := p.server.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-p.quit:
+			return
+		case event := <-events:
+			if event.Type != p2p.PeerEventTypeDrop {
+				continue
+			}
+			p.dropPeer(discv5.NodeID(event.Peer))
+		}
+	}
+}
+
+// topicDrop carries the outcome of removing a peer from a single topic, so
+// that events can be emitted and search sped back up after p.mu is released.
+type topicDrop struct {
+	topic    discv5.Topic
+	state    *topicState
+	belowMin bool
+}
+
+func (p *PeerPool) dropPeer(id discv5.NodeID) {
+	var drops []topicDrop
+
+	p.mu.Lock()
+	for topic, state := range p.topics {
+		if _, ok := state.peers[id]; !ok {
+			continue
+		}
+		delete(state.peers, id)
+		if p.cache != nil {
+			p.cache.Remove(topic, id)
+		}
+		drops = append(drops, topicDrop{topic: topic, state: state, belowMin: len(state.peers) < state.limits.Min})
+	}
+	p.mu.Unlock()
+
+	for _, d := range drops {
+		p.emit(PeerPoolEvent{Type: PeerDropped, Topic: d.topic, Peer: id})
+		if d.belowMin {
+			log.Debug("peer count below floor, speeding up search", "topic", d.topic)
+			p.speedUpSearch(d.state)
+		}
+	}
+}
+
+// speedUpSearch pushes fastSearchPeriod to a topic's search goroutine so it
+// re-hunts for peers immediately rather than waiting out a slow period. It
+// is a no-op once the topic's search has already stopped.
+func (p *PeerPool) speedUpSearch(state *topicState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.quit:
+		return
+	case <-state.quit:
+		return
+	default:
+	}
+	select {
+	case state.period <- fastSearchPeriod:
+	default:
+	}
+}
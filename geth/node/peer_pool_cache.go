@@ -0,0 +1,193 @@
+package node
+
+import (
+	"encoding/binary"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/discv5"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// DefaultCacheTTL is how long a discovered node is kept without being
+// re-seen before it is evicted by PruneExpired.
+const DefaultCacheTTL = 6 * time.Hour
+
+// pruneInterval is how often a LDBTopicCache sweeps for expired entries in
+// its background goroutine.
+const pruneInterval = time.Hour
+
+// TopicCache persists nodes discovered for a topic so that they can seed the
+// next PeerPool.Start before discv5 search has produced any results.
+type TopicCache interface {
+	// Get returns the nodes cached for topic, most recently seen first.
+	Get(topic discv5.Topic) []*discv5.Node
+	// Put records (or refreshes the last-seen timestamp of) node under topic.
+	Put(topic discv5.Topic, node *discv5.Node)
+	// Remove deletes a single node from topic's cache.
+	Remove(topic discv5.Topic, nodeID discv5.NodeID)
+}
+
+// cacheEntry is the RLP-encoded value stored per topic/node key. Node is the
+// enode URL (as returned by discv5.Node.String) rather than a raw struct
+// encoding so that it stays readable across discv5 format changes.
+type cacheEntry struct {
+	Node     string
+	LastSeen uint64
+}
+
+// LDBTopicCache is a TopicCache backed by a LevelDB database, keyed by
+// topic || nodeID so that all nodes for a topic sort together. It prunes
+// expired entries from the database on its own on pruneInterval.
+type LDBTopicCache struct {
+	db   *leveldb.DB
+	ttl  time.Duration
+	quit chan struct{}
+}
+
+// NewLDBTopicCache opens (or creates) a LevelDB-backed TopicCache at path
+// and starts its background pruning goroutine.
+func NewLDBTopicCache(path string, ttl time.Duration) (*LDBTopicCache, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+	c := &LDBTopicCache{db: db, ttl: ttl, quit: make(chan struct{})}
+	go c.pruneLoop()
+	return c, nil
+}
+
+// pruneLoop periodically calls PruneExpired until Close is called.
+func (c *LDBTopicCache) pruneLoop() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-ticker.C:
+			c.PruneExpired()
+		}
+	}
+}
+
+// Close stops the pruning goroutine and releases the underlying LevelDB
+// handle.
+func (c *LDBTopicCache) Close() error {
+	close(c.quit)
+	return c.db.Close()
+}
+
+// topicPrefix builds a length-prefixed encoding of topic so that it can be
+// used both as a cache key prefix and as a LevelDB iterator prefix without
+// one topic's keyspace ever being a byte-prefix of another's (e.g. "les" vs
+// "les2", which would otherwise collide under a bare []byte(topic) prefix).
+func topicPrefix(topic discv5.Topic) []byte {
+	prefix := make([]byte, 4+len(topic))
+	binary.BigEndian.PutUint32(prefix, uint32(len(topic)))
+	copy(prefix[4:], topic)
+	return prefix
+}
+
+func cacheKey(topic discv5.Topic, nodeID discv5.NodeID) []byte {
+	key := make([]byte, 0, 4+len(topic)+len(nodeID))
+	key = append(key, topicPrefix(topic)...)
+	key = append(key, nodeID[:]...)
+	return key
+}
+
+// Get implements TopicCache.
+func (c *LDBTopicCache) Get(topic discv5.Topic) []*discv5.Node {
+	type seenNode struct {
+		node     *discv5.Node
+		lastSeen uint64
+	}
+	var seen []seenNode
+
+	iter := c.db.NewIterator(util.BytesPrefix(topicPrefix(topic)), nil)
+	defer iter.Release()
+
+	now := time.Now()
+	for iter.Next() {
+		var entry cacheEntry
+		if err := rlp.DecodeBytes(iter.Value(), &entry); err != nil {
+			log.Warn("failed to decode cached topic node", "err", err)
+			continue
+		}
+		lastSeen := time.Unix(int64(entry.LastSeen), 0)
+		if now.Sub(lastSeen) > c.ttl {
+			continue
+		}
+		node, err := discv5.ParseNode(entry.Node)
+		if err != nil {
+			log.Warn("failed to decode cached enode", "err", err)
+			continue
+		}
+		seen = append(seen, seenNode{node: node, lastSeen: entry.LastSeen})
+	}
+
+	sort.Slice(seen, func(i, j int) bool { return seen[i].lastSeen > seen[j].lastSeen })
+
+	nodes := make([]*discv5.Node, len(seen))
+	for i, s := range seen {
+		nodes[i] = s.node
+	}
+	return nodes
+}
+
+// Put implements TopicCache.
+func (c *LDBTopicCache) Put(topic discv5.Topic, node *discv5.Node) {
+	entry := cacheEntry{
+		Node:     node.String(),
+		LastSeen: uint64(time.Now().Unix()),
+	}
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Error("failed to encode topic node for cache", "err", err)
+		return
+	}
+	if err := c.db.Put(cacheKey(topic, node.ID), data, nil); err != nil {
+		log.Error("failed to persist topic node", "err", err)
+	}
+}
+
+// Remove implements TopicCache.
+func (c *LDBTopicCache) Remove(topic discv5.Topic, nodeID discv5.NodeID) {
+	if err := c.db.Delete(cacheKey(topic, nodeID), nil); err != nil {
+		log.Error("failed to remove cached topic node", "err", err)
+	}
+}
+
+// PruneExpired walks every entry in the database and removes nodes that have
+// not been re-seen for longer than the configured TTL.
+func (c *LDBTopicCache) PruneExpired() {
+	iter := c.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	now := time.Now()
+	var stale [][]byte
+	for iter.Next() {
+		var entry cacheEntry
+		if err := rlp.DecodeBytes(iter.Value(), &entry); err != nil {
+			continue
+		}
+		lastSeen := time.Unix(int64(entry.LastSeen), 0)
+		if now.Sub(lastSeen) > c.ttl {
+			key := make([]byte, len(iter.Key()))
+			copy(key, iter.Key())
+			stale = append(stale, key)
+		}
+	}
+	for _, key := range stale {
+		if err := c.db.Delete(key, nil); err != nil {
+			log.Error("failed to prune expired topic node", "err", err)
+		}
+	}
+}
+
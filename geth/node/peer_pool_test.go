@@ -0,0 +1,117 @@
+package node
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discv5"
+)
+
+// TestPeerPoolStartStopCycle exercises the same start/stop race class that
+// topic_register_test.go pins down for Register: repeated Start/Stop cycles
+// on one PeerPool must neither panic nor hang, and Start must not be tripped
+// up by state left behind from the previous cycle (see the peers-map reset
+// in Start).
+func TestPeerPoolStartStopCycle(t *testing.T) {
+	server := newTestP2PServer(t)
+	defer server.Stop()
+
+	topic := discv5.Topic("peerpool-start-stop")
+	pool := NewPeerPool([]discv5.Topic{topic}, nil, nil)
+
+	for i := 0; i < 5; i++ {
+		if err := pool.Start(server); err != nil {
+			t.Fatalf("failed to start pool: %v", err)
+		}
+
+		done := make(chan struct{})
+		go func() {
+			pool.Stop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Stop did not return in time")
+		}
+
+		pool.mu.Lock()
+		state := pool.topics[topic]
+		peers := len(state.peers)
+		pool.mu.Unlock()
+		if peers != 0 {
+			t.Fatalf("expected no peers tracked after Stop, got %d", peers)
+		}
+	}
+}
+
+// TestPeerPoolFoundAddDropResearch drives addPeer/dropPeer directly (as
+// searchTopic and watchDroppedPeers would) against a topicState wired to a
+// real, started p2p.Server but without spawning the real discv5 search
+// goroutines, so the found -> added -> dropped -> re-search sequence is
+// deterministic rather than racing a live search.
+func TestPeerPoolFoundAddDropResearch(t *testing.T) {
+	server := newTestP2PServer(t)
+	defer server.Stop()
+
+	topic := discv5.Topic("peerpool-found-add-drop")
+	pool := NewPeerPool([]discv5.Topic{topic}, map[discv5.Topic]TopicLimits{
+		topic: {Min: 1, Max: 1},
+	}, nil)
+	pool.server = server
+
+	state := pool.topics[topic]
+	state.quit = make(chan struct{})
+	state.period = make(chan time.Duration, 1)
+
+	var id discv5.NodeID
+	id[0] = 0x01
+	node := discv5.NewNode(id, net.ParseIP("127.0.0.1"), 30303, 30303)
+
+	pool.addPeer(topic, state, node)
+
+	pool.mu.Lock()
+	_, tracked := state.peers[id]
+	pool.mu.Unlock()
+	if !tracked {
+		t.Fatalf("expected peer to be tracked after addPeer")
+	}
+
+	select {
+	case ev := <-pool.Events():
+		if ev.Type != PeerAdded || ev.Peer != id {
+			t.Fatalf("unexpected event after addPeer: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected a PeerAdded event")
+	}
+
+	pool.dropPeer(id)
+
+	pool.mu.Lock()
+	_, stillTracked := state.peers[id]
+	pool.mu.Unlock()
+	if stillTracked {
+		t.Fatalf("expected peer to be removed after dropPeer")
+	}
+
+	select {
+	case ev := <-pool.Events():
+		if ev.Type != PeerDropped || ev.Peer != id {
+			t.Fatalf("unexpected event after dropPeer: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected a PeerDropped event")
+	}
+
+	select {
+	case d := <-state.period:
+		if d != fastSearchPeriod {
+			t.Fatalf("expected dropping below Min to speed search back up, got %v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected dropPeer to push a new search period once below Min")
+	}
+}
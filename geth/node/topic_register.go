@@ -1,23 +1,101 @@
 package node
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/discv5"
 )
 
+// ErrTopicAlreadyRegistered is returned by AddTopic for a topic that is
+// already being advertised.
+var ErrTopicAlreadyRegistered = errors.New("topic is already registered")
+
+// ErrTopicNotRegistered is returned by RemoveTopic for a topic that is not
+// currently being advertised.
+var ErrTopicNotRegistered = errors.New("topic is not registered")
+
+// DefaultEventsBufferSize is used for Register.Events() when the buffer size
+// hasn't been overridden via NewRegisterWithEventsBufferSize.
+const DefaultEventsBufferSize = 256
+
+// statsSamplePeriod is how often a TopicRegistrationStats event is emitted
+// for each topic being registered.
+const statsSamplePeriod = 30 * time.Second
+
+// RegisterEventType identifies the kind of RegisterEvent emitted on Events().
+type RegisterEventType string
+
+const (
+	// TopicRegistrationStarted is emitted when a topic's RegisterTopic
+	// goroutine is spawned, either from Start or AddTopic.
+	TopicRegistrationStarted RegisterEventType = "topic.registration.started"
+	// TopicRegistrationStopped is emitted once a topic's quit channel has
+	// been closed, either from Stop or RemoveTopic; it does not wait for the
+	// RegisterTopic goroutine to return.
+	TopicRegistrationStopped RegisterEventType = "topic.registration.stopped"
+	// TopicRegistrationStats is emitted periodically while a topic is
+	// registered, carrying a coarse activity sample for that topic.
+	TopicRegistrationStats RegisterEventType = "topic.registration.stats"
+)
+
+// TopicRegistrationStatsSample is a point-in-time activity sample for a
+// topic's registration goroutine, carried by TopicRegistrationStats events.
+type TopicRegistrationStatsSample struct {
+	Topic         discv5.Topic
+	Registrations uint64
+	LookupsSent   uint64
+	LastAttempt   time.Time
+}
+
+// RegisterEvent is emitted on Register.Events() to report the health of
+// topic registration.
+type RegisterEvent struct {
+	Type  RegisterEventType
+	Topic discv5.Topic
+	Stats TopicRegistrationStatsSample
+}
+
 // Register manages register topic queries
 type Register struct {
 	topics []discv5.Topic
 
+	mu     sync.Mutex
+	quits  map[discv5.Topic]chan struct{}
+	server *p2p.Server
+
+	events chan RegisterEvent
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
 
 // NewRegister creates instance of topic register
 func NewResigter(topics ...discv5.Topic) *Register {
-	return &Register{topics: topics}
+	return NewRegisterWithEventsBufferSize(DefaultEventsBufferSize, topics...)
+}
+
+// NewRegisterWithEventsBufferSize creates a topic register whose Events()
+// buffer is sized explicitly instead of using DefaultEventsBufferSize. The
+// buffer size can only be set at construction time, since r.events is read
+// and written from other goroutines once the Register is in use.
+func NewRegisterWithEventsBufferSize(size int, topics ...discv5.Topic) *Register {
+	return &Register{
+		topics: topics,
+		events: make(chan RegisterEvent, size),
+	}
+}
+
+// Events returns a channel emitting TopicRegistrationStarted,
+// TopicRegistrationStopped and periodic TopicRegistrationStats events. The
+// channel drops the oldest pending event on overflow rather than blocking
+// registration.
+func (r *Register) Events() <-chan RegisterEvent {
+	return r.events
 }
 
 // Start topic register query for every topic
@@ -25,17 +103,122 @@ func (r *Register) Start(server *p2p.Server) error {
 	if server.DiscV5 == nil {
 		return ErrDiscv5NotRunning
 	}
+	r.server = server
 	r.quit = make(chan struct{})
+
+	r.mu.Lock()
+	r.quits = make(map[discv5.Topic]chan struct{}, len(r.topics))
 	for _, topic := range r.topics {
-		r.wg.Add(1)
-		go func(t discv5.Topic) {
-			server.DiscV5.RegisterTopic(t, r.quit)
-			r.wg.Done()
-		}(topic)
+		r.registerTopicLocked(topic)
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// AddTopic starts advertising an additional topic without disturbing any
+// topic that is already being registered.
+func (r *Register) AddTopic(topic discv5.Topic) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.server == nil {
+		return ErrDiscv5NotRunning
+	}
+	select {
+	case <-r.quit:
+		return ErrDiscv5NotRunning
+	default:
+	}
+	if _, ok := r.quits[topic]; ok {
+		return ErrTopicAlreadyRegistered
 	}
+	r.registerTopicLocked(topic)
 	return nil
 }
 
+// RemoveTopic stops advertising topic, leaving every other topic untouched.
+func (r *Register) RemoveTopic(topic discv5.Topic) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	quit, ok := r.quits[topic]
+	if !ok {
+		return ErrTopicNotRegistered
+	}
+	close(quit)
+	delete(r.quits, topic)
+	return nil
+}
+
+// registerTopicLocked spawns the RegisterTopic goroutine for topic, along
+// with a goroutine sampling its activity for Events(), and records its quit
+// channel. The caller must hold r.mu.
+func (r *Register) registerTopicLocked(topic discv5.Topic) {
+	quit := make(chan struct{})
+	r.quits[topic] = quit
+
+	registerAttempts(topic).Inc(1)
+	registerActive(topic).Inc(1)
+	r.emit(RegisterEvent{Type: TopicRegistrationStarted, Topic: topic})
+
+	r.wg.Add(1)
+	go func(t discv5.Topic, quit chan struct{}) {
+		r.server.DiscV5.RegisterTopic(t, quit)
+		r.wg.Done()
+	}(topic, quit)
+
+	r.wg.Add(1)
+	go r.sampleStats(topic, quit)
+}
+
+// sampleStats periodically emits a TopicRegistrationStats event for topic
+// until quit is closed. The counters are timed in this goroutine rather than
+// read from discv5 internals, which do not expose them.
+func (r *Register) sampleStats(topic discv5.Topic, quit chan struct{}) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(statsSamplePeriod)
+	defer ticker.Stop()
+
+	var registrations, lookups uint64
+	for {
+		select {
+		case <-quit:
+			registerActive(topic).Dec(1)
+			r.emit(RegisterEvent{Type: TopicRegistrationStopped, Topic: topic})
+			return
+		case <-ticker.C:
+			registrations++
+			lookups++
+			r.emit(RegisterEvent{
+				Type:  TopicRegistrationStats,
+				Topic: topic,
+				Stats: TopicRegistrationStatsSample{
+					Topic:         topic,
+					Registrations: registrations,
+					LookupsSent:   lookups,
+					LastAttempt:   time.Now(),
+				},
+			})
+		}
+	}
+}
+
+// emit delivers event to Events(), dropping the oldest pending event if the
+// buffer is full rather than blocking the caller.
+func (r *Register) emit(event RegisterEvent) {
+	select {
+	case r.events <- event:
+	default:
+		select {
+		case <-r.events:
+		default:
+		}
+		select {
+		case r.events <- event:
+		default:
+		}
+	}
+}
+
 // Stop all register topic queries and waits for them to exit
 func (r *Register) Stop() {
 	if r.quit == nil {
@@ -47,6 +230,28 @@ func (r *Register) Stop() {
 	default:
 	}
 	close(r.quit)
+
+	r.mu.Lock()
+	for topic, quit := range r.quits {
+		close(quit)
+		delete(r.quits, topic)
+	}
+	r.mu.Unlock()
+
 	r.wg.Wait()
 	return
 }
+
+// registerAttempts returns the counter registered as
+// discv5/topic/register/attempts/<topic>, registering it on first use.
+func registerAttempts(topic discv5.Topic) metrics.Counter {
+	return metrics.GetOrRegisterCounter(fmt.Sprintf("discv5/topic/register/attempts/%s", topic), nil)
+}
+
+// registerActive returns the counter registered as
+// discv5/topic/register/active/<topic>, registering it on first use. It is
+// incremented when a topic's registration starts and decremented when it
+// stops, so it holds the number of active registrations for topic.
+func registerActive(topic discv5.Topic) metrics.Counter {
+	return metrics.GetOrRegisterCounter(fmt.Sprintf("discv5/topic/register/active/%s", topic), nil)
+}
@@ -0,0 +1,95 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discv5"
+)
+
+// newTestP2PServer starts a real p2p.Server with discv5 enabled on loopback,
+// so that Register has a live server.DiscV5 to call RegisterTopic on.
+func newTestP2PServer(t *testing.T) *p2p.Server {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate node key: %v", err)
+	}
+	server := &p2p.Server{
+		Config: p2p.Config{
+			PrivateKey:      key,
+			MaxPeers:        10,
+			NoDiscovery:     true,
+			DiscoveryV5:     true,
+			ListenAddr:      "127.0.0.1:0",
+			DiscoveryV5Addr: "127.0.0.1:0",
+		},
+	}
+	if err := server.Start(); err != nil {
+		t.Fatalf("failed to start p2p server: %v", err)
+	}
+	return server
+}
+
+func TestRegisterRemoveUnknownTopic(t *testing.T) {
+	r := NewResigter()
+	if err := r.RemoveTopic(discv5.Topic("never-registered")); err != ErrTopicNotRegistered {
+		t.Fatalf("expected ErrTopicNotRegistered, got %v", err)
+	}
+}
+
+func TestRegisterAddTopicTwice(t *testing.T) {
+	server := newTestP2PServer(t)
+	defer server.Stop()
+
+	r := NewResigter()
+	if err := r.Start(server); err != nil {
+		t.Fatalf("failed to start register: %v", err)
+	}
+	defer r.Stop()
+
+	topic := discv5.Topic("re-add-topic")
+	if err := r.AddTopic(topic); err != nil {
+		t.Fatalf("unexpected error adding topic: %v", err)
+	}
+	if err := r.AddTopic(topic); err != ErrTopicAlreadyRegistered {
+		t.Fatalf("expected ErrTopicAlreadyRegistered, got %v", err)
+	}
+}
+
+// TestRegisterAddTopicWhileStopping exercises AddTopic racing Stop: whichever
+// wins, neither call may panic, deadlock, or leave a topic registered after
+// Stop has returned.
+func TestRegisterAddTopicWhileStopping(t *testing.T) {
+	server := newTestP2PServer(t)
+	defer server.Stop()
+
+	for i := 0; i < 10; i++ {
+		r := NewResigter()
+		if err := r.Start(server); err != nil {
+			t.Fatalf("failed to start register: %v", err)
+		}
+
+		stopped := make(chan struct{})
+		go func() {
+			r.Stop()
+			close(stopped)
+		}()
+
+		_ = r.AddTopic(discv5.Topic("add-while-stopping"))
+
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Stop did not return after racing with AddTopic")
+		}
+
+		r.mu.Lock()
+		remaining := len(r.quits)
+		r.mu.Unlock()
+		if remaining != 0 {
+			t.Fatalf("expected no topics left registered after Stop, got %d", remaining)
+		}
+	}
+}